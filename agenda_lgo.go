@@ -1,14 +1,18 @@
 package agenda_lgo
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 const baseURL = "https://agenda-lgo.de/api"
@@ -39,12 +43,44 @@ type DocumentResponse []struct {
 	DocumentList  []Document  `json:"documents"`
 }
 
+// EmployerDocuments groups the documents belonging to one employer. An
+// Agenda LGO account can have more than one entry, e.g. after a job change
+// or for someone with several concurrent mandates.
+type EmployerDocuments struct {
+	Employer      string
+	Employee      string
+	ActivationKey interface{}
+	Documents     []Document
+}
+
 // LGO represents the API of "Agenda: Lohn- und Gehaltsdokumente"
 type LGO struct {
-	client       *http.Client
+	client      *http.Client
+	credentials CredentialProvider
+	outDir      string
+
+	// sessionMu guards sessionToken and sessionExpiresAt, which are read from
+	// generateURL and written from Login. Both SaveAll and the agenda-lgod
+	// daemon can have several goroutines driving the same *LGO at once.
+	sessionMu    sync.Mutex
 	sessionToken string
-	authFilePath string
-	outDir       string
+	// sessionExpiresAt is our own estimate of when sessionToken stops being
+	// accepted, since the API doesn't tell us. It is refreshed on every
+	// successful Login using sessionTTL.
+	sessionExpiresAt time.Time
+	sessionTTL       time.Duration
+
+	// loginMu serializes Login calls so that several goroutines racing to
+	// authenticate the first request don't each fire their own Login; the
+	// first one in logs in, the rest just pick up the session it left behind.
+	loginMu sync.Mutex
+
+	maxRetries  int
+	baseBackoff time.Duration
+	sem         chan struct{}
+	limiter     *rate.Limiter
+	store       Store
+	sink        Sink
 }
 
 // URPResponse The response from "Agenda LGO" which contains the session-token
@@ -53,79 +89,106 @@ type URPResponse struct {
 }
 
 // NewLGO Instanciates a new LGO-instance
-func NewLGO(authFilePath, outDir string) *LGO {
+func NewLGO(credentials CredentialProvider, outDir string, opts ...Option) *LGO {
 	lgo := &LGO{
-		authFilePath: authFilePath,
-		outDir:       outDir,
+		credentials: credentials,
+		outDir:      outDir,
+		maxRetries:  defaultMaxRetries,
+		baseBackoff: defaultBaseBackoff,
+		sessionTTL:  defaultSessionTTL,
+		sem:         make(chan struct{}, defaultConcurrency),
+		sink:        NewFileSink(outDir),
 	}
 	transport := &http.Transport{}
 	lgo.client = &http.Client{
 		Transport: transport,
 	}
 
+	for _, opt := range opts {
+		opt(lgo)
+	}
+
 	return lgo
 }
 
 // SaveDocument Saves the document in the specified out-path
-func (lgo *LGO) SaveDocument(document Document) error {
-	downloadPath := lgo.generateURL(document.DownloadPath + "/" + document.Name)
-
-	req, err := http.NewRequest("GET", downloadPath, nil)
+func (lgo *LGO) SaveDocument(ctx context.Context, document Document) error {
+	release, err := lgo.acquire(ctx)
 	if err != nil {
 		return err
 	}
-	lgo.setHeaders(req)
+	defer release()
 
-	resp, err := lgo.client.Do(req)
+	key := fmt.Sprintf("%d-%s.pdf", document.Year, time.Month(document.Month))
+	_, err = lgo.downloadDocument(ctx, document, key)
+	return err
+}
+
+// downloadDocument downloads document and hands it to the configured Sink
+// under key, returning the SHA-256 of the bytes written so callers like
+// Sync can record it for later dedup. Callers are responsible
+// for bounding concurrency via acquire.
+func (lgo *LGO) downloadDocument(ctx context.Context, document Document, key string) (sha256sum string, err error) {
+	resp, err := lgo.doAuthenticated(ctx, "GET", document.DownloadPath+"/"+document.Name, nil)
 	if err != nil {
-		return err
+		return "", err
 	}
-
 	defer resp.Body.Close()
 
-	out, err := os.Create(fmt.Sprintf("%s/%d-%s.pdf", lgo.outDir, document.Year, time.Month(document.Month)))
-	if err != nil {
-		return err
+	hasher := sha256.New()
+	if err := lgo.sink.Put(ctx, key, io.TeeReader(resp.Body, hasher), document); err != nil {
+		return "", err
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
-// FetchDocumentList Fetches the list of all available documents
-func (lgo *LGO) FetchDocumentList() ([]Document, error) {
-	// Fetch all documents
-	req, err := http.NewRequest("GET", lgo.generateURL("/me/e"), nil)
+// FetchDocumentList Fetches the list of all available documents, across
+// every employer on the account.
+func (lgo *LGO) FetchDocumentList(ctx context.Context) ([]Document, error) {
+	employers, err := lgo.FetchEmployerDocuments(ctx)
 	if err != nil {
 		return nil, err
 	}
-	lgo.setHeaders(req)
 
-	resp, err := lgo.client.Do(req)
+	var documents []Document
+	for _, employer := range employers {
+		documents = append(documents, employer.Documents...)
+	}
+
+	return documents, nil
+}
+
+// FetchEmployerDocuments Fetches the documents for every employer on the
+// account, keeping them grouped by employer.
+func (lgo *LGO) FetchEmployerDocuments(ctx context.Context) ([]EmployerDocuments, error) {
+	resp, err := lgo.doAuthenticated(ctx, "GET", "/me/e", nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	documentResponse := DocumentResponse{}
-	err = json.NewDecoder(resp.Body).Decode(&documentResponse)
-	if err != nil {
+	if err := json.NewDecoder(resp.Body).Decode(&documentResponse); err != nil {
 		return nil, err
 	}
 
-	return documentResponse[0].DocumentList, nil
+	employers := make([]EmployerDocuments, 0, len(documentResponse))
+	for _, entry := range documentResponse {
+		employers = append(employers, EmployerDocuments{
+			Employer:      entry.Employer,
+			Employee:      entry.Employee,
+			ActivationKey: entry.ActivationKey,
+			Documents:     entry.DocumentList,
+		})
+	}
+
+	return employers, nil
 }
 
 // generateAuthentication Generates the neccessary reader for the login
-func (lgo *LGO) generateAuthentication() (*strings.Reader, error) {
-	reader, err := os.Open(lgo.authFilePath)
-	if err != nil {
-		return nil, err
-	}
-
-	auth := Authentication{}
-	err = json.NewDecoder(reader).Decode(&auth)
+func (lgo *LGO) generateAuthentication(ctx context.Context) (*strings.Reader, error) {
+	auth, err := lgo.credentials.Credentials(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -135,26 +198,26 @@ func (lgo *LGO) generateAuthentication() (*strings.Reader, error) {
 	return strings.NewReader(authStr), nil
 }
 
-// Login Logs into "Agenda: LGO"
-func (lgo *LGO) Login() error {
+// Login Logs into "Agenda: LGO". Callers racing to authenticate should go
+// through ensureSession/refreshSession instead of calling Login directly, so
+// only one Login is ever in flight at a time.
+func (lgo *LGO) Login(ctx context.Context) error {
 	// First login
 
-	authenticationReader, err := lgo.generateAuthentication()
+	authenticationReader, err := lgo.generateAuthentication(ctx)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", lgo.generateURL("/auth"), authenticationReader)
+	resp, err := lgo.do(ctx, "POST", lgo.generateURL("/auth"), authenticationReader)
 	if err != nil {
 		return err
 	}
-	lgo.setHeaders(req)
+	defer resp.Body.Close()
 
-	resp, err := lgo.client.Do(req)
-	if err != nil {
+	if err := classifyStatus(resp.StatusCode, resp.Status, true); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
 	urpResponse := URPResponse{}
 
@@ -163,28 +226,88 @@ func (lgo *LGO) Login() error {
 		return err
 	}
 
-	lgo.sessionToken = urpResponse.URP
+	lgo.setSessionToken(urpResponse.URP)
 
 	// Strange, but we need a second login via GET
-	req, err = http.NewRequest("GET", lgo.generateURL("/auth"), nil)
+	resp, err = lgo.do(ctx, "GET", lgo.generateURL("/auth"), nil)
 	if err != nil {
 		return err
 	}
-	lgo.setHeaders(req)
+	defer resp.Body.Close()
 
-	resp, err = lgo.client.Do(req)
-	if err != nil {
+	if err := classifyStatus(resp.StatusCode, resp.Status, true); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return errors.New(resp.Status)
-	}
+	lgo.sessionMu.Lock()
+	lgo.sessionExpiresAt = time.Now().Add(lgo.sessionTTL)
+	lgo.sessionMu.Unlock()
 
 	return nil
 }
 
+// ensureSession makes sure the client has a usable session token before an
+// authenticated request, serializing Login across concurrent callers (e.g.
+// SaveAll's worker pool) so a cold client fires one Login instead of one per
+// in-flight download.
+func (lgo *LGO) ensureSession(ctx context.Context) error {
+	if lgo.sessionValid() {
+		return nil
+	}
+
+	lgo.loginMu.Lock()
+	defer lgo.loginMu.Unlock()
+
+	// Another goroutine may have logged in while we were waiting for the
+	// lock.
+	if lgo.sessionValid() {
+		return nil
+	}
+
+	return lgo.Login(ctx)
+}
+
+// refreshSession is like ensureSession, but forces a re-login even though our
+// own bookkeeping still thinks the session is valid, since the server has
+// just told us otherwise via a session-expired response. staleToken is the
+// token the caller saw fail, so concurrent callers that hit the same 401
+// don't each trigger their own re-login.
+func (lgo *LGO) refreshSession(ctx context.Context, staleToken string) error {
+	lgo.loginMu.Lock()
+	defer lgo.loginMu.Unlock()
+
+	if lgo.currentSessionToken() != staleToken {
+		// Someone else already refreshed it for us.
+		return nil
+	}
+
+	return lgo.Login(ctx)
+}
+
+// sessionValid reports whether sessionToken is set and sessionExpiresAt
+// hasn't passed yet, according to our own estimate.
+func (lgo *LGO) sessionValid() bool {
+	lgo.sessionMu.Lock()
+	defer lgo.sessionMu.Unlock()
+
+	return lgo.sessionToken != "" && time.Now().Before(lgo.sessionExpiresAt)
+}
+
+// currentSessionToken returns the session token as of now.
+func (lgo *LGO) currentSessionToken() string {
+	lgo.sessionMu.Lock()
+	defer lgo.sessionMu.Unlock()
+
+	return lgo.sessionToken
+}
+
+// setSessionToken stores the token obtained from a Login response.
+func (lgo *LGO) setSessionToken(token string) {
+	lgo.sessionMu.Lock()
+	lgo.sessionToken = token
+	lgo.sessionMu.Unlock()
+}
+
 // setHeaders Sets the neccessary headers
 func (lgo *LGO) setHeaders(req *http.Request) {
 	req.Header.Set("Origin", "https://agenda-lgo.de")
@@ -194,5 +317,5 @@ func (lgo *LGO) setHeaders(req *http.Request) {
 
 // generateURl Generates the URL
 func (lgo *LGO) generateURL(method string) string {
-	return baseURL + method + lgo.sessionToken
+	return baseURL + method + lgo.currentSessionToken()
 }
@@ -0,0 +1,149 @@
+package agenda_lgo
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultMaxRetries  = 3
+	defaultBaseBackoff = 500 * time.Millisecond
+	// defaultSessionTTL is how long we assume a session token stays valid.
+	// The API never tells us its actual expiry, so this is an observed,
+	// conservative estimate rather than a documented guarantee.
+	defaultSessionTTL = 25 * time.Minute
+	// defaultConcurrency bounds how many downloads may be in flight at once
+	// unless overridden via WithConcurrency.
+	defaultConcurrency = 4
+)
+
+// do builds and executes an HTTP request, retrying retriable failures
+// (connection errors and 5xx responses) with exponential backoff and
+// jitter, and waiting on the configured rate limiter before every attempt,
+// retries included. Callers are responsible for closing the returned
+// response's body.
+func (lgo *LGO) do(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	lgo.setHeaders(req)
+
+	var lastErr error
+	for attempt := 0; attempt <= lgo.maxRetries; attempt++ {
+		if attempt > 0 {
+			if req.GetBody != nil {
+				newBody, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = newBody
+			}
+			if err := waitBackoff(ctx, lgo.baseBackoff, attempt); err != nil {
+				return nil, err
+			}
+		}
+
+		if lgo.limiter != nil {
+			if err := lgo.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := lgo.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError && attempt < lgo.maxRetries {
+			resp.Body.Close()
+			lastErr = &APIError{Kind: ErrKindServer, StatusCode: resp.StatusCode, Status: resp.Status}
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, lastErr
+}
+
+// doAuthenticated is like do, but for endpoints that require a valid session
+// token: it makes sure Login has run at least once, and if the request comes
+// back with an expired/invalid session, it transparently re-runs Login once
+// and retries before surfacing the error to the caller.
+func (lgo *LGO) doAuthenticated(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	if err := lgo.ensureSession(ctx); err != nil {
+		return nil, err
+	}
+
+	staleToken := lgo.currentSessionToken()
+
+	resp, err := lgo.do(ctx, method, lgo.generateURL(path), body)
+	if err != nil {
+		return nil, err
+	}
+
+	statusErr := classifyStatus(resp.StatusCode, resp.Status, false)
+	if statusErr == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	if !IsSessionExpired(statusErr) {
+		return nil, statusErr
+	}
+
+	if err := lgo.refreshSession(ctx, staleToken); err != nil {
+		return nil, err
+	}
+
+	resp, err = lgo.do(ctx, method, lgo.generateURL(path), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusErr := classifyStatus(resp.StatusCode, resp.Status, false); statusErr != nil {
+		resp.Body.Close()
+		return nil, statusErr
+	}
+
+	return resp, nil
+}
+
+// acquire reserves a slot in the concurrency semaphore, blocking until one is
+// free or ctx is done. NewLGO always sets a limit (defaultConcurrency unless
+// WithConcurrency overrides it); acquire is a no-op only when sem is nil.
+func (lgo *LGO) acquire(ctx context.Context) (func(), error) {
+	if lgo.sem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case lgo.sem <- struct{}{}:
+		return func() { <-lgo.sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// waitBackoff sleeps for an exponentially increasing, jittered backoff
+// before the next retry attempt, returning early with ctx.Err() if ctx is
+// cancelled first.
+func waitBackoff(ctx context.Context, base time.Duration, attempt int) error {
+	d := base * time.Duration(int64(1)<<uint(attempt-1))
+	d += time.Duration(rand.Int63n(int64(d)/2 + 1))
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"gitlab.com/jannickfahlbusch/agenda_lgo"
+)
+
+var (
+	authFilePath string
+	out          string
+	storePath    string
+	addr         string
+	token        string
+	pollInterval time.Duration
+	smtpAddr     string
+	smtpUser     string
+	smtpPassword string
+	smtpFrom     string
+	smtpTo       string
+	webhookURL   string
+)
+
+func init() {
+	flag.StringVar(&authFilePath, "a", ".auth", "Path to the authentication-file")
+	flag.StringVar(&out, "o", "out", "Where to store downloaded documents: a plain path, or a file://, s3://, or webdav:// URL")
+	flag.StringVar(&storePath, "store", ".agenda-lgo.json", "Path to the local JSON index file used to track already-downloaded documents")
+	flag.StringVar(&addr, "addr", ":8080", "Address to listen on")
+	flag.StringVar(&token, "token", os.Getenv("AGENDA_LGOD_TOKEN"), "Bearer token required on every request (defaults to $AGENDA_LGOD_TOKEN)")
+	flag.DurationVar(&pollInterval, "poll-interval", 30*time.Minute, "How often to poll Agenda LGO for new documents")
+	flag.StringVar(&smtpAddr, "smtp-addr", "", "SMTP server address (host:port) used to notify about new documents by email")
+	flag.StringVar(&smtpUser, "smtp-user", os.Getenv("SMTP_USERNAME"), "SMTP username (defaults to $SMTP_USERNAME)")
+	flag.StringVar(&smtpPassword, "smtp-password", os.Getenv("SMTP_PASSWORD"), "SMTP password (defaults to $SMTP_PASSWORD)")
+	flag.StringVar(&smtpFrom, "smtp-from", "", "From address for email notifications")
+	flag.StringVar(&smtpTo, "smtp-to", "", "To address for email notifications")
+	flag.StringVar(&webhookURL, "webhook-url", "", "URL to POST a JSON notification to when new documents are found")
+}
+
+func main() {
+	flag.Parse()
+
+	if token == "" {
+		log.Fatal("a bearer token is required, set -token or AGENDA_LGOD_TOKEN")
+	}
+
+	sink, err := agenda_lgo.NewSinkFromURL(out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	store, err := agenda_lgo.NewJSONStore(storePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lgo := agenda_lgo.NewLGO(
+		agenda_lgo.NewFileCredentialProvider(authFilePath),
+		out,
+		agenda_lgo.WithStore(store),
+		agenda_lgo.WithSink(sink),
+	)
+
+	notifiers := buildNotifiers()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// pollLoop and a manual POST /sync both drive lgo.Sync; routing both
+	// through the same syncRunner keeps them from racing over the session
+	// and the Store.
+	runner := &syncRunner{lgo: lgo}
+
+	go pollLoop(ctx, runner, pollInterval, notifiers)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /documents", handleListDocuments(store))
+	mux.HandleFunc("POST /sync", handleSync(runner))
+	mux.HandleFunc("GET /documents/{id}/pdf", handleDownloadPDF(store, sink))
+
+	server := &http.Server{Addr: addr, Handler: requireBearerToken(token, mux)}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("agenda-lgod listening on %s", addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
+
+func buildNotifiers() []agenda_lgo.Notifier {
+	var notifiers []agenda_lgo.Notifier
+
+	if smtpAddr != "" {
+		var auth smtp.Auth
+		if smtpUser != "" {
+			auth = smtp.PlainAuth("", smtpUser, smtpPassword, strings.Split(smtpAddr, ":")[0])
+		}
+		notifiers = append(notifiers, agenda_lgo.NewEmailNotifier(smtpAddr, auth, smtpFrom, smtpTo))
+	}
+
+	if webhookURL != "" {
+		notifiers = append(notifiers, agenda_lgo.NewWebhookNotifier(webhookURL))
+	}
+
+	return notifiers
+}
+
+// syncRunner serializes calls to LGO.Sync so the background poller and a
+// manually triggered POST /sync can never run concurrently against the same
+// session and Store.
+type syncRunner struct {
+	lgo *agenda_lgo.LGO
+
+	mu sync.Mutex
+}
+
+func (r *syncRunner) Sync(ctx context.Context, opts agenda_lgo.SyncOptions) ([]agenda_lgo.SyncedDocument, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.lgo.Sync(ctx, opts)
+}
+
+// pollLoop runs Sync on every tick of interval and fires every notifier for
+// each document that came back new, until ctx is done.
+func pollLoop(ctx context.Context, runner *syncRunner, interval time.Duration, notifiers []agenda_lgo.Notifier) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		synced, err := runner.Sync(ctx, agenda_lgo.SyncOptions{})
+		if err != nil {
+			log.Printf("sync failed: %v", err)
+		}
+
+		for _, s := range synced {
+			log.Printf("new document: %s %s %d", s.Employer, time.Month(s.Document.Month), s.Document.Year)
+
+			for _, notifier := range notifiers {
+				if err := notifier.Notify(ctx, s.Employer, s.Document); err != nil {
+					log.Printf("notify failed: %v", err)
+				}
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+
+		if len(got) != len(expected) || subtle.ConstantTimeCompare(got, expected) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func handleListDocuments(store *agenda_lgo.JSONStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		records, err := store.All(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, records)
+	}
+}
+
+func handleSync(runner *syncRunner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		synced, err := runner.Sync(r.Context(), agenda_lgo.SyncOptions{})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, synced)
+	}
+}
+
+// handleDownloadPDF serves the raw PDF for a previously synced document.
+// Streaming back the original bytes is only supported when documents are
+// stored on the local filesystem; other sinks don't expose a way to read
+// back what was written.
+func handleDownloadPDF(store *agenda_lgo.JSONStore, sink agenda_lgo.Sink) http.HandlerFunc {
+	fileSink, isFileSink := sink.(*agenda_lgo.FileSink)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, ok, err := store.Get(r.Context(), r.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		if !isFileSink {
+			http.Error(w, "serving PDFs is only supported for the local filesystem sink", http.StatusNotImplemented)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pdf")
+		http.ServeFile(w, r, filepath.Join(fileSink.BaseDir, record.Key))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("encoding response: %v", err)
+	}
+}
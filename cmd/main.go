@@ -1,35 +1,82 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
+	"time"
 
+	"filippo.io/age"
 	"gitlab.com/jannickfahlbusch/agenda_lgo"
 )
 
 var (
 	authFilePath string
 	out          string
+	storePath    string
+	since        string
+	year         int
+	month        int
+	force        bool
+	employer     string
+	encryptTo    string
 )
 
 func init() {
 	flag.StringVar(&authFilePath, "a", ".auth", "Path to the authentication-file")
-	flag.StringVar(&out, "o", "out", "Path to the directory where the files should be stored, must exist")
+	flag.StringVar(&out, "o", "out", "Where to store downloaded documents: a plain path, or a file://, s3://, or webdav:// URL")
+	flag.StringVar(&storePath, "store", ".agenda-lgo.json", "Path to the local JSON index file used to track already-downloaded documents")
+	flag.StringVar(&since, "since", "", "Only sync documents created on or after this date (YYYY-MM-DD)")
+	flag.IntVar(&year, "year", 0, "Only sync documents from this year")
+	flag.IntVar(&month, "month", 0, "Only sync documents from this month")
+	flag.BoolVar(&force, "force", false, "Re-download documents even if already present in the store")
+	flag.StringVar(&employer, "employer", "", "Only sync documents for this employer (by exact name)")
+	flag.StringVar(&encryptTo, "encrypt-to", "", "age public key to encrypt documents to before writing them to the sink")
 }
 
 func main() {
 	flag.Parse()
 
-	lgo := agenda_lgo.NewLGO(authFilePath, out)
-	err := lgo.Login()
+	ctx := context.Background()
+
+	sink, err := agenda_lgo.NewSinkFromURL(out)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	documentList, err := lgo.FetchDocumentList()
+	if encryptTo != "" {
+		recipient, err := age.ParseX25519Recipient(encryptTo)
+		if err != nil {
+			log.Fatalf("invalid -encrypt-to recipient: %v", err)
+		}
+		sink = agenda_lgo.NewEncryptingSink(sink, recipient)
+	}
+
+	store, err := agenda_lgo.NewJSONStore(storePath)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	for _, doc := range documentList {
-		lgo.SaveDocument(doc)
+	lgo := agenda_lgo.NewLGO(
+		agenda_lgo.NewFileCredentialProvider(authFilePath),
+		out,
+		agenda_lgo.WithStore(store),
+		agenda_lgo.WithSink(sink),
+	)
+
+	opts := agenda_lgo.SyncOptions{Year: year, Month: month, Force: force, Employer: employer}
+	if since != "" {
+		t, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			log.Fatalf("invalid -since date: %v", err)
+		}
+		opts.Since = t
+	}
+
+	synced, err := lgo.Sync(ctx, opts)
+	if err != nil {
+		log.Fatal(err)
 	}
 
+	log.Printf("synced %d document(s)", len(synced))
 }
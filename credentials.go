@@ -0,0 +1,95 @@
+package agenda_lgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/zalando/go-keyring"
+)
+
+// CredentialProvider supplies the Authentication used by Login. Implementations
+// may read from a file, the environment, an OS keyring, or anywhere else.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (Authentication, error)
+}
+
+// FileCredentialProvider reads Authentication from a JSON file, the
+// long-standing way of configuring this library.
+type FileCredentialProvider struct {
+	Path string
+}
+
+// NewFileCredentialProvider returns a CredentialProvider backed by the JSON
+// file at path.
+func NewFileCredentialProvider(path string) *FileCredentialProvider {
+	return &FileCredentialProvider{Path: path}
+}
+
+// Credentials implements CredentialProvider.
+func (p *FileCredentialProvider) Credentials(ctx context.Context) (Authentication, error) {
+	file, err := os.Open(p.Path)
+	if err != nil {
+		return Authentication{}, err
+	}
+	defer file.Close()
+
+	auth := Authentication{}
+	if err := json.NewDecoder(file).Decode(&auth); err != nil {
+		return Authentication{}, err
+	}
+
+	return auth, nil
+}
+
+// EnvCredentialProvider reads Authentication from environment variables,
+// AGENDA_LGO_EMAIL and AGENDA_LGO_PASSWORD by default.
+type EnvCredentialProvider struct {
+	EmailVar    string
+	PasswordVar string
+}
+
+// NewEnvCredentialProvider returns a CredentialProvider backed by the
+// AGENDA_LGO_EMAIL and AGENDA_LGO_PASSWORD environment variables.
+func NewEnvCredentialProvider() *EnvCredentialProvider {
+	return &EnvCredentialProvider{EmailVar: "AGENDA_LGO_EMAIL", PasswordVar: "AGENDA_LGO_PASSWORD"}
+}
+
+// Credentials implements CredentialProvider.
+func (p *EnvCredentialProvider) Credentials(ctx context.Context) (Authentication, error) {
+	email, ok := os.LookupEnv(p.EmailVar)
+	if !ok {
+		return Authentication{}, fmt.Errorf("agenda_lgo: environment variable %s is not set", p.EmailVar)
+	}
+
+	password, ok := os.LookupEnv(p.PasswordVar)
+	if !ok {
+		return Authentication{}, fmt.Errorf("agenda_lgo: environment variable %s is not set", p.PasswordVar)
+	}
+
+	return Authentication{Email: email, Password: password}, nil
+}
+
+// KeyringCredentialProvider reads the password from the OS keyring (via
+// go-keyring), keyed by service and email.
+type KeyringCredentialProvider struct {
+	Service string
+	Email   string
+}
+
+// NewKeyringCredentialProvider returns a CredentialProvider backed by the OS
+// keyring entry stored under service/email.
+func NewKeyringCredentialProvider(service, email string) *KeyringCredentialProvider {
+	return &KeyringCredentialProvider{Service: service, Email: email}
+}
+
+// Credentials implements CredentialProvider.
+func (p *KeyringCredentialProvider) Credentials(ctx context.Context) (Authentication, error) {
+	password, err := keyring.Get(p.Service, p.Email)
+	if err != nil {
+		return Authentication{}, fmt.Errorf("agenda_lgo: reading keyring entry: %w", err)
+	}
+
+	return Authentication{Email: p.Email, Password: password}, nil
+}
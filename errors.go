@@ -0,0 +1,86 @@
+package agenda_lgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies why an API call to "Agenda LGO" failed.
+type ErrorKind int
+
+const (
+	// ErrKindUnknown is returned for non-2xx responses that don't fit one of
+	// the other, more specific kinds.
+	ErrKindUnknown ErrorKind = iota
+	// ErrKindAuth means the credentials used during Login were rejected.
+	ErrKindAuth
+	// ErrKindSessionExpired means the session token is no longer accepted.
+	ErrKindSessionExpired
+	// ErrKindServer means the API responded with a transient 5xx error.
+	ErrKindServer
+)
+
+// APIError is returned whenever "Agenda LGO" responds with a non-2xx status.
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int
+	Status     string
+}
+
+func (e *APIError) Error() string {
+	switch e.Kind {
+	case ErrKindAuth:
+		return fmt.Sprintf("agenda_lgo: authentication failed (%s)", e.Status)
+	case ErrKindSessionExpired:
+		return fmt.Sprintf("agenda_lgo: session expired (%s)", e.Status)
+	case ErrKindServer:
+		return fmt.Sprintf("agenda_lgo: server error (%s)", e.Status)
+	default:
+		return fmt.Sprintf("agenda_lgo: unexpected response (%s)", e.Status)
+	}
+}
+
+// IsAuthError reports whether err indicates that the configured credentials
+// were rejected by Login.
+func IsAuthError(err error) bool {
+	return hasKind(err, ErrKindAuth)
+}
+
+// IsSessionExpired reports whether err indicates that the session token is
+// no longer accepted and a re-login is required.
+func IsSessionExpired(err error) bool {
+	return hasKind(err, ErrKindSessionExpired)
+}
+
+// IsServerError reports whether err indicates a transient, server-side
+// failure that may succeed if retried.
+func IsServerError(err error) bool {
+	return hasKind(err, ErrKindServer)
+}
+
+func hasKind(err error, kind ErrorKind) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind == kind
+	}
+	return false
+}
+
+// classifyStatus turns a non-2xx HTTP status into a typed *APIError.
+// loginPhase distinguishes a rejected login (ErrKindAuth) from a session
+// that was accepted before but no longer is (ErrKindSessionExpired).
+func classifyStatus(statusCode int, status string, loginPhase bool) error {
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		return nil
+	case statusCode == 401 || statusCode == 403:
+		if loginPhase {
+			return &APIError{Kind: ErrKindAuth, StatusCode: statusCode, Status: status}
+		}
+		return &APIError{Kind: ErrKindSessionExpired, StatusCode: statusCode, Status: status}
+	case statusCode >= 500:
+		return &APIError{Kind: ErrKindServer, StatusCode: statusCode, Status: status}
+	default:
+		return &APIError{Kind: ErrKindUnknown, StatusCode: statusCode, Status: status}
+	}
+}
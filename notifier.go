@@ -0,0 +1,10 @@
+package agenda_lgo
+
+import "context"
+
+// Notifier alerts a human (or another system) that a new payslip is
+// available. Sync does not call notifiers itself; agenda-lgod's poll loop
+// calls Notify for every document a Sync run returns.
+type Notifier interface {
+	Notify(ctx context.Context, employer string, document Document) error
+}
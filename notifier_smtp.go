@@ -0,0 +1,58 @@
+package agenda_lgo
+
+import (
+	"bytes"
+	"context"
+	"net/smtp"
+	"text/template"
+	"time"
+)
+
+const defaultEmailTemplate = `Subject: New payslip from {{.Employer}}
+
+Your payslip for {{.Month}} {{.Year}} from {{.Employer}} is now available.
+`
+
+// EmailNotifier sends an email via SMTP whenever a new document is synced.
+type EmailNotifier struct {
+	Addr string // SMTP server address, e.g. "smtp.example.com:587"
+	Auth smtp.Auth
+	From string
+	To   []string
+
+	// Template renders the message, headers included. It defaults to a
+	// minimal built-in template and is executed with a struct that has
+	// Employer, Year, and Month fields.
+	Template *template.Template
+}
+
+// NewEmailNotifier returns an EmailNotifier that authenticates with auth and
+// sends from `from` to every address in `to`.
+func NewEmailNotifier(addr string, auth smtp.Auth, from string, to ...string) *EmailNotifier {
+	return &EmailNotifier{Addr: addr, Auth: auth, From: from, To: to}
+}
+
+// Notify implements Notifier.
+func (n *EmailNotifier) Notify(ctx context.Context, employer string, document Document) error {
+	tmpl := n.Template
+	if tmpl == nil {
+		var err error
+		tmpl, err = template.New("email").Parse(defaultEmailTemplate)
+		if err != nil {
+			return err
+		}
+	}
+
+	data := struct {
+		Employer string
+		Year     int
+		Month    time.Month
+	}{Employer: employer, Year: document.Year, Month: time.Month(document.Month)}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return err
+	}
+
+	return smtp.SendMail(n.Addr, n.Auth, n.From, n.To, body.Bytes())
+}
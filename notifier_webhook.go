@@ -0,0 +1,71 @@
+package agenda_lgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookPayload is the JSON body WebhookNotifier posts, generic enough for
+// Slack/Discord/ntfy/Matrix-style integrations that accept a simple "text"
+// field.
+type WebhookPayload struct {
+	Text     string `json:"text"`
+	Employer string `json:"employer"`
+	Year     int    `json:"year"`
+	Month    int    `json:"month"`
+}
+
+// WebhookNotifier POSTs a WebhookPayload to URL whenever a new document is
+// synced.
+type WebhookNotifier struct {
+	Client *http.Client
+	URL    string
+}
+
+// NewWebhookNotifier returns a WebhookNotifier posting to url using
+// http.DefaultClient.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{Client: http.DefaultClient, URL: url}
+}
+
+// Notify implements Notifier.
+func (n *WebhookNotifier) Notify(ctx context.Context, employer string, document Document) error {
+	payload := WebhookPayload{
+		Text:     fmt.Sprintf("New payslip from %s is available: %s %d", employer, time.Month(document.Month), document.Year),
+		Employer: employer,
+		Year:     document.Year,
+		Month:    document.Month,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("agenda_lgo: webhook returned %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package agenda_lgo
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Option configures an LGO instance created via NewLGO.
+type Option func(*LGO)
+
+// WithMaxRetries sets how many times a retriable request (connection errors,
+// 5xx responses) is retried before giving up. The default is 3.
+func WithMaxRetries(n int) Option {
+	return func(lgo *LGO) { lgo.maxRetries = n }
+}
+
+// WithBackoff sets the base duration used for the exponential backoff
+// between retries; jitter is added on top of it. The default is 500ms.
+func WithBackoff(base time.Duration) Option {
+	return func(lgo *LGO) { lgo.baseBackoff = base }
+}
+
+// WithConcurrency bounds how many downloads may be in flight at the same
+// time. The default is 4.
+func WithConcurrency(n int) Option {
+	return func(lgo *LGO) { lgo.sem = make(chan struct{}, n) }
+}
+
+// WithRateLimiter attaches a rate.Limiter that every request has to pass
+// through first, so the client never hammers the Agenda API. There is no
+// limiter by default.
+func WithRateLimiter(limiter *rate.Limiter) Option {
+	return func(lgo *LGO) { lgo.limiter = limiter }
+}
+
+// WithSessionTTL overrides how long a session token is assumed to stay valid
+// before doAuthenticated proactively re-runs Login. The default is 25
+// minutes.
+func WithSessionTTL(ttl time.Duration) Option {
+	return func(lgo *LGO) { lgo.sessionTTL = ttl }
+}
+
+// WithStore attaches the Store used by Sync to keep track of already
+// downloaded documents. There is none by default, so Sync returns an error
+// until one is configured.
+func WithStore(store Store) Option {
+	return func(lgo *LGO) { lgo.store = store }
+}
+
+// WithSink overrides where downloaded documents are written. The default is
+// a FileSink rooted at outDir; pass an S3Sink, WebDAVSink, or an
+// EncryptingSink wrapping either to send documents elsewhere.
+func WithSink(sink Sink) Option {
+	return func(lgo *LGO) { lgo.sink = sink }
+}
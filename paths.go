@@ -0,0 +1,36 @@
+package agenda_lgo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// employerDocumentKey builds the Sink key a document for employer is
+// written under when downloaded via SaveAll or Sync:
+// <employer-slug>/<year>-<Month>.pdf.
+func employerDocumentKey(employer string, document Document) string {
+	return fmt.Sprintf("%s/%d-%s.pdf", employerSlug(employer), document.Year, time.Month(document.Month))
+}
+
+// employerSlug turns an employer name into a lowercase, filesystem-safe
+// directory name, e.g. "Muster GmbH & Co. KG" -> "muster-gmbh-co-kg".
+func employerSlug(employer string) string {
+	var b strings.Builder
+
+	lastWasDash := true // avoid a leading dash
+	for _, r := range strings.ToLower(employer) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			b.WriteRune(r)
+			lastWasDash = false
+		default:
+			if !lastWasDash {
+				b.WriteByte('-')
+				lastWasDash = true
+			}
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,78 @@
+package agenda_lgo
+
+import (
+	"context"
+	"sync"
+)
+
+// SaveAllOptions configures SaveAll.
+type SaveAllOptions struct {
+	// Employer restricts SaveAll to the employer whose name matches exactly.
+	// Empty means every employer on the account.
+	Employer string
+}
+
+// SaveAll downloads every document for every employer on the account
+// (optionally narrowed down to one via SaveAllOptions.Employer), fanning the
+// downloads out across a worker pool bounded by WithConcurrency. Files are
+// written to <outDir>/<employer-slug>/<year>-<Month>.pdf.
+func (lgo *LGO) SaveAll(ctx context.Context, opts SaveAllOptions) error {
+	employers, err := lgo.FetchEmployerDocuments(ctx)
+	if err != nil {
+		return err
+	}
+
+	type job struct {
+		employer string
+		document Document
+	}
+
+	var jobs []job
+	for _, employer := range employers {
+		if opts.Employer != "" && employer.Employer != opts.Employer {
+			continue
+		}
+		for _, document := range employer.Documents {
+			jobs = append(jobs, job{employer: employer.Employer, document: document})
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	for _, j := range jobs {
+		release, err := lgo.acquire(ctx)
+		if err != nil {
+			// Don't abandon already-launched workers still holding a
+			// semaphore slot and writing to the sink; drain them via
+			// wg.Wait() below before reporting the ctx error.
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = err
+			}
+			mu.Unlock()
+			break
+		}
+
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			defer release()
+
+			key := employerDocumentKey(j.employer, j.document)
+			if _, err := lgo.downloadDocument(ctx, j.document, key); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(j)
+	}
+
+	wg.Wait()
+	return firstErr
+}
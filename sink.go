@@ -0,0 +1,46 @@
+package agenda_lgo
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Sink is where a downloaded Document's bytes end up. Implementations
+// include the local filesystem, S3-compatible object storage, WebDAV, and an
+// encrypting wrapper around any other Sink.
+type Sink interface {
+	// Put writes the content of r under key, a slash-separated path such as
+	// "muster-gmbh/2026-January.pdf".
+	Put(ctx context.Context, key string, r io.Reader, meta Document) error
+}
+
+// FileSink writes documents to the local filesystem, rooted at BaseDir. It
+// is the default Sink.
+type FileSink struct {
+	BaseDir string
+}
+
+// NewFileSink returns a Sink that writes documents under baseDir.
+func NewFileSink(baseDir string) *FileSink {
+	return &FileSink{BaseDir: baseDir}
+}
+
+// Put implements Sink.
+func (s *FileSink) Put(ctx context.Context, key string, r io.Reader, meta Document) error {
+	path := filepath.Join(s.BaseDir, key)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, r)
+	return err
+}
@@ -0,0 +1,51 @@
+package agenda_lgo
+
+import (
+	"context"
+	"io"
+
+	"filippo.io/age"
+)
+
+// EncryptingSink wraps another Sink and age-encrypts bytes to Recipients
+// before handing them to it, so the inner Sink only ever sees ciphertext.
+type EncryptingSink struct {
+	Inner      Sink
+	Recipients []age.Recipient
+}
+
+// NewEncryptingSink returns a Sink that age-encrypts every Put to one or
+// more recipients before passing it on to inner.
+func NewEncryptingSink(inner Sink, recipients ...age.Recipient) *EncryptingSink {
+	return &EncryptingSink{Inner: inner, Recipients: recipients}
+}
+
+// Put implements Sink. The key gets a ".age" suffix so encrypted and
+// plaintext sinks never collide on the same object name.
+func (s *EncryptingSink) Put(ctx context.Context, key string, r io.Reader, meta Document) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		w, err := age.Encrypt(pw, s.Recipients...)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		if _, err := io.Copy(w, r); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		pw.CloseWithError(w.Close())
+	}()
+
+	// If Inner.Put fails before fully draining pr (e.g. an upload auth
+	// error), close pr with that error too so the encrypt goroutine's
+	// pending pw.Write unblocks instead of leaking forever.
+	err := s.Inner.Put(ctx, key+".age", pr, meta)
+	if err != nil {
+		pr.CloseWithError(err)
+	}
+	return err
+}
@@ -0,0 +1,33 @@
+package agenda_lgo
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// S3Sink writes documents to an S3-compatible bucket via minio-go, which
+// also covers non-AWS S3 implementations.
+type S3Sink struct {
+	Client *minio.Client
+	Bucket string
+	Prefix string
+}
+
+// NewS3Sink returns a Sink that writes documents to bucket, under prefix,
+// using client.
+func NewS3Sink(client *minio.Client, bucket, prefix string) *S3Sink {
+	return &S3Sink{Client: client, Bucket: bucket, Prefix: prefix}
+}
+
+// Put implements Sink.
+func (s *S3Sink) Put(ctx context.Context, key string, r io.Reader, meta Document) error {
+	object := path.Join(s.Prefix, key)
+
+	_, err := s.Client.PutObject(ctx, s.Bucket, object, r, -1, minio.PutObjectOptions{
+		ContentType: "application/pdf",
+	})
+	return err
+}
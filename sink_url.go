@@ -0,0 +1,67 @@
+package agenda_lgo
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/studio-b12/gowebdav"
+)
+
+// NewSinkFromURL builds a Sink from rawURL, picking the implementation by
+// scheme:
+//
+//	(no scheme), or file:///abs/path -> FileSink
+//	s3://bucket/prefix                -> S3Sink
+//	webdav://host/prefix              -> WebDAVSink
+//
+// S3 and WebDAV credentials are read from the environment
+// (the AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY/S3_ENDPOINT and
+// WEBDAV_USERNAME/WEBDAV_PASSWORD variables, respectively), the same way
+// EnvCredentialProvider keeps secrets out of the configuration itself.
+func NewSinkFromURL(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "", "file":
+		path := u.Path
+		if path == "" {
+			path = rawURL
+		}
+		return NewFileSink(path), nil
+
+	case "s3":
+		endpoint := os.Getenv("S3_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "s3.amazonaws.com"
+		}
+
+		client, err := minio.New(endpoint, &minio.Options{
+			Creds:  credentials.NewEnvAWS(),
+			Secure: os.Getenv("S3_INSECURE") == "",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("agenda_lgo: creating S3 client: %w", err)
+		}
+
+		return NewS3Sink(client, u.Host, strings.TrimPrefix(u.Path, "/")), nil
+
+	case "webdav":
+		client := gowebdav.NewClient(
+			fmt.Sprintf("https://%s", u.Host),
+			os.Getenv("WEBDAV_USERNAME"),
+			os.Getenv("WEBDAV_PASSWORD"),
+		)
+
+		return NewWebDAVSink(client, strings.TrimPrefix(u.Path, "/")), nil
+
+	default:
+		return nil, fmt.Errorf("agenda_lgo: unsupported sink scheme %q", u.Scheme)
+	}
+}
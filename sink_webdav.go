@@ -0,0 +1,43 @@
+package agenda_lgo
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"github.com/studio-b12/gowebdav"
+)
+
+// WebDAVSink writes documents to a WebDAV server, e.g. a Nextcloud or
+// ownCloud instance, via gowebdav.
+type WebDAVSink struct {
+	Client *gowebdav.Client
+	Prefix string
+}
+
+// NewWebDAVSink returns a Sink that writes documents under prefix using
+// client. gowebdav has no context support of its own, so ctx is only
+// honoured up to cancellation before the write starts.
+func NewWebDAVSink(client *gowebdav.Client, prefix string) *WebDAVSink {
+	return &WebDAVSink{Client: client, Prefix: prefix}
+}
+
+// Put implements Sink.
+func (s *WebDAVSink) Put(ctx context.Context, key string, r io.Reader, meta Document) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	object := path.Join(s.Prefix, key)
+
+	if err := s.Client.MkdirAll(path.Dir(object), 0o755); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return s.Client.Write(object, data, 0o644)
+}
@@ -0,0 +1,108 @@
+package agenda_lgo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Record is what a Store keeps about one downloaded document, used by Sync
+// to decide whether it needs to be downloaded again.
+type Record struct {
+	ID        string    `json:"id"`
+	Employer  string    `json:"employer"`
+	Year      int       `json:"year"`
+	Month     int       `json:"month"`
+	SHA256    string    `json:"sha256"`
+	Key       string    `json:"key"` // key the document was written under in the Sink
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Store records which documents have already been downloaded, so Sync can
+// skip them on later runs.
+type Store interface {
+	// Get returns the Record for id, or ok == false if there is none.
+	Get(ctx context.Context, id string) (record Record, ok bool, err error)
+	// Put creates or replaces the Record stored under record.ID.
+	Put(ctx context.Context, record Record) error
+	// All returns every Record currently in the store.
+	All(ctx context.Context) ([]Record, error)
+}
+
+// JSONStore is a Store backed by a single JSON index file. It is the
+// simplest option and needs no external dependencies, at the cost of
+// rewriting the whole file on every Put.
+type JSONStore struct {
+	path string
+
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+// NewJSONStore opens the JSONStore backed by the file at path, starting from
+// an empty index if the file doesn't exist yet. The file itself is first
+// written by Put.
+func NewJSONStore(path string) (*JSONStore, error) {
+	store := &JSONStore{path: path, records: map[string]Record{}}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(&store.records); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Get implements Store.
+func (s *JSONStore) Get(ctx context.Context, id string) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[id]
+	return record, ok, nil
+}
+
+// Put implements Store.
+func (s *JSONStore) Put(ctx context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[record.ID] = record
+	return s.save()
+}
+
+// All implements Store.
+func (s *JSONStore) All(ctx context.Context) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]Record, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// save writes the current records to disk. Callers must hold s.mu.
+func (s *JSONStore) save() error {
+	file, err := os.Create(s.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s.records)
+}
@@ -0,0 +1,161 @@
+package agenda_lgo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SyncOptions filters which documents a Sync run considers.
+type SyncOptions struct {
+	// Since restricts sync to documents created at or after this time. The
+	// zero value means no restriction.
+	Since time.Time
+	// Year restricts sync to documents from this year. 0 means no
+	// restriction.
+	Year int
+	// Month restricts sync to documents from this month. 0 means no
+	// restriction.
+	Month int
+	// Force re-downloads documents even if the store already has a record
+	// for them.
+	Force bool
+	// Employer restricts sync to the employer whose name matches exactly.
+	// Empty means every employer on the account.
+	Employer string
+}
+
+// SyncedDocument pairs a newly downloaded Document with the employer it
+// belongs to, since Document alone doesn't carry that information.
+type SyncedDocument struct {
+	Employer string
+	Document Document
+}
+
+// Sync fetches the remote document list and downloads every document that
+// isn't already recorded in the Store, or whose recorded SHA-256 no longer
+// matches what's on disk, skipping the rest. It returns the documents that
+// were actually downloaded. A Store must be configured via WithStore.
+//
+// The API exposes no hash or size for a document before downloading it, so
+// "changed" can only be detected locally: recordStillValid re-reads the file
+// a FileSink previously wrote and compares its hash against the Record,
+// catching local corruption or deletion rather than a new revision on the
+// server.
+func (lgo *LGO) Sync(ctx context.Context, opts SyncOptions) ([]SyncedDocument, error) {
+	if lgo.store == nil {
+		return nil, fmt.Errorf("agenda_lgo: Sync requires a Store, configure one with WithStore")
+	}
+
+	employers, err := lgo.FetchEmployerDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var synced []SyncedDocument
+	for _, employer := range employers {
+		if opts.Employer != "" && employer.Employer != opts.Employer {
+			continue
+		}
+
+		for _, document := range employer.Documents {
+			if !matchesSyncFilter(document, opts) {
+				continue
+			}
+
+			key := documentKey(employer.Employer, document)
+
+			if !opts.Force {
+				record, ok, err := lgo.store.Get(ctx, key)
+				if err != nil {
+					return nil, err
+				}
+				if ok && lgo.recordStillValid(record) {
+					continue
+				}
+			}
+
+			sinkKey := employerDocumentKey(employer.Employer, document)
+
+			release, err := lgo.acquire(ctx)
+			if err != nil {
+				return nil, err
+			}
+			sum, err := lgo.downloadDocument(ctx, document, sinkKey)
+			release()
+			if err != nil {
+				return nil, err
+			}
+
+			record := Record{
+				ID:        key,
+				Employer:  employer.Employer,
+				Year:      document.Year,
+				Month:     document.Month,
+				SHA256:    sum,
+				Key:       sinkKey,
+				CreatedAt: time.Now(),
+			}
+			if err := lgo.store.Put(ctx, record); err != nil {
+				return nil, err
+			}
+
+			synced = append(synced, SyncedDocument{Employer: employer.Employer, Document: document})
+		}
+	}
+
+	return synced, nil
+}
+
+// documentKey derives a stable identifier for a Document. The API doesn't
+// expose a per-document ID, so employer+year+month+name has to stand in for
+// one.
+func documentKey(employer string, document Document) string {
+	return fmt.Sprintf("%s-%04d-%02d-%s", employer, document.Year, document.Month, document.Name)
+}
+
+// recordStillValid reports whether record's SHA-256 still matches what's on
+// disk, so Sync can tell "already downloaded" apart from "downloaded, then
+// changed or lost underneath us". Only FileSink supports reading back what
+// was written; for every other Sink we have no way to check, so we trust the
+// Store until opts.Force says otherwise.
+func (lgo *LGO) recordStillValid(record Record) bool {
+	fileSink, ok := lgo.sink.(*FileSink)
+	if !ok {
+		return true
+	}
+
+	f, err := os.Open(filepath.Join(fileSink.BaseDir, record.Key))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return false
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)) == record.SHA256
+}
+
+// matchesSyncFilter reports whether document passes the Since/Year/Month
+// restrictions in opts.
+func matchesSyncFilter(document Document, opts SyncOptions) bool {
+	if opts.Year != 0 && document.Year != opts.Year {
+		return false
+	}
+	if opts.Month != 0 && document.Month != opts.Month {
+		return false
+	}
+	if !opts.Since.IsZero() && time.Unix(document.CreatedAt, 0).Before(opts.Since) {
+		return false
+	}
+
+	return true
+}